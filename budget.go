@@ -0,0 +1,101 @@
+package emit
+
+import "time"
+
+// Budget limits Ticker emission by the cost of delivered ticks (e.g. API credits per hour).
+// Every tick is charged by Cost; once the budget can't pay for a tick, emission is paused
+// and all ticks are dropped until the budget is replenished to Capacity every Interval.
+type Budget struct {
+	// Capacity is the amount of credits available per Interval. Zero Capacity disables budgeting,
+	// negative one is not allowed.
+	Capacity int64
+	// Interval is a replenishment period. Must be positive if Capacity is set.
+	Interval time.Duration
+	// Cost returns the non-negative price of the tick. Nil Cost charges 1 credit for each tick.
+	// Tick costing more than Capacity is charged the whole Capacity, so it is emitted on full budget only.
+	// Cost is called on the Ticker goroutine, so it must not call Ticker methods other than
+	// Ack, Stats, History and Healthy: others wait for the Ticker goroutine and deadlock.
+	Cost func(tick time.Time) int64
+	// OnResume is called when exhausted budget is replenished and emission resumes.
+	// It is called asynchronously, so it may call any Ticker method.
+	OnResume func(now time.Time)
+}
+
+// budget is a running state of Budget owned by the Ticker goroutine
+type budget struct {
+	Budget
+
+	left      int64
+	exhausted bool
+	ticker    *time.Ticker
+}
+
+func newBudget(cfg Budget) budget {
+	b := budget{Budget: cfg}
+	if cfg.Capacity < 0 {
+		panic("emit: negative budget capacity")
+	}
+	if cfg.Capacity == 0 {
+		return b
+	}
+	if cfg.Interval <= 0 {
+		panic("emit: non-positive budget interval")
+	}
+
+	b.left = cfg.Capacity
+	b.ticker = time.NewTicker(cfg.Interval)
+
+	return b
+}
+
+// refill returns replenishment channel or nil if budgeting is disabled
+func (b *budget) refill() <-chan time.Time {
+	if b.ticker == nil {
+		return nil
+	}
+	return b.ticker.C
+}
+
+// spend charges the tick and reports if it can be emitted
+func (b *budget) spend(tick time.Time) bool {
+	if b.Capacity == 0 {
+		return true
+	}
+	if b.exhausted {
+		return false
+	}
+
+	cost := int64(1)
+	if b.Cost != nil {
+		cost = b.Cost(tick)
+	}
+	if cost < 0 {
+		panic("emit: negative budget cost")
+	}
+	if cost > b.Capacity {
+		cost = b.Capacity
+	}
+	if cost > b.left {
+		b.exhausted = true
+		return false
+	}
+
+	b.left -= cost
+	return true
+}
+
+// replenish refills the budget and reports if emission is resumed
+func (b *budget) replenish() bool {
+	b.left = b.Capacity
+
+	resumed := b.exhausted
+	b.exhausted = false
+
+	return resumed
+}
+
+func (b *budget) stop() {
+	if b.ticker != nil {
+		b.ticker.Stop()
+	}
+}
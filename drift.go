@@ -0,0 +1,100 @@
+package emit
+
+import (
+	"time"
+
+	"github.com/pshch-pshch/chia"
+)
+
+// wallTicker is a time.Ticker replacement for long periods.
+// Instead of sleeping the whole period on a single monotonic timer it wakes up at least every check interval
+// and recomputes the sleep target against the wall clock, so clock skew of the process doesn't accumulate.
+type wallTicker struct {
+	C <-chan time.Time
+
+	c    chan time.Time
+	wall func() time.Time
+	stop *chia.Signal
+}
+
+// newWallTicker starts wallTicker reporting every observed wall clock correction to onCorrection.
+// Nil wall means time.Now.
+func newWallTicker(d, check time.Duration, wall func() time.Time, onCorrection func(time.Duration)) *wallTicker {
+	c := make(chan time.Time, 1)
+
+	if wall == nil {
+		wall = time.Now
+	}
+
+	w := &wallTicker{
+		C: c, c: c,
+
+		wall: wall,
+		stop: chia.NewSignal(),
+	}
+
+	go w.run(d, check, onCorrection)
+
+	return w
+}
+
+// Stop turns off wallTicker. Like time.Ticker.Stop it doesn't close the channel.
+func (w *wallTicker) Stop() {
+	w.stop.Close()
+}
+
+func (w *wallTicker) run(d, check time.Duration, onCorrection func(time.Duration)) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
+	next := w.now().Add(d)
+	for {
+		armed, armedWall := time.Now(), w.now()
+		sleep := next.Sub(armedWall)
+		if sleep > check {
+			sleep = check
+		}
+		timer.Reset(sleep)
+
+		select {
+		case <-w.stop.C:
+			return
+		case <-timer.C:
+		}
+
+		// Wall and monotonic clocks are read together, so timer latency doesn't count as skew
+		now := time.Now()
+		wall := w.now()
+		if correction := wallCorrection(armedWall, wall, now.Sub(armed)); correction != 0 {
+			onCorrection(correction)
+		}
+
+		if wall.Before(next) {
+			continue
+		}
+
+		// Like time.Ticker, drop ticks for slow receivers
+		select {
+		case w.c <- now:
+		default:
+		}
+
+		// Skip missed periods
+		next = next.Add(d)
+		if !wall.Before(next) {
+			next = next.Add((wall.Sub(next)/d + 1) * d)
+		}
+	}
+}
+
+// now returns the wall clock time without monotonic clock reading
+func (w *wallTicker) now() time.Time {
+	return w.wall().Round(0)
+}
+
+// wallCorrection returns the wall clock skew: the difference between wall clock elapsed time
+// from armed till now and the elapsed monotonic time
+func wallCorrection(armed, now time.Time, elapsed time.Duration) time.Duration {
+	return now.Sub(armed) - elapsed
+}
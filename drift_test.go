@@ -0,0 +1,59 @@
+package emit_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pshch-pshch/emit"
+)
+
+func TestWallCorrection(t *testing.T) {
+	armed := time.Date(2026, 1, 1, 3, 0, 0, 0, time.UTC)
+
+	for _, c := range []struct {
+		wall       time.Duration
+		monotonic  time.Duration
+		correction time.Duration
+	}{
+		{time.Hour, time.Hour, 0},
+		{time.Hour + time.Second, time.Hour, time.Second},  // Wall clock is ahead of process clock
+		{time.Hour - time.Second, time.Hour, -time.Second}, // Wall clock is behind process clock
+	} {
+		if correction := emit.WallCorrection(armed, armed.Add(c.wall), c.monotonic); correction != c.correction {
+			t.Fatalf("Correction of %s wall and %s monotonic elapsed time is %s, expected %s",
+				c.wall, c.monotonic, correction, c.correction)
+		}
+	}
+}
+
+func TestTicker_WallClockJump(t *testing.T) {
+	var offset int64
+	ticker := emit.TickerConfig{
+		DriftCheck: 5 * time.Millisecond,
+		WallClock: func() time.Time {
+			return time.Now().Add(time.Duration(atomic.LoadInt64(&offset)))
+		},
+	}.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	slop := 10 * time.Millisecond
+	time.Sleep(20 * time.Millisecond)
+	if correction := ticker.Stats().DriftCorrection; correction < -slop || correction > slop {
+		t.Fatalf("Stats drift correction is %s before wall clock jump", correction)
+	}
+
+	// Wall clock jumps past the scheduled tick
+	jump := time.Hour
+	atomic.StoreInt64(&offset, int64(jump))
+
+	select {
+	case <-ticker.C:
+	case <-time.After(time.Second):
+		t.Fatal("Ticker didn't fire after wall clock jump")
+	}
+
+	if correction := ticker.Stats().DriftCorrection; correction < jump-slop || correction > jump+slop {
+		t.Fatalf("Stats drift correction is %s, expected [%s,%s]", correction, jump-slop, jump+slop)
+	}
+}
@@ -0,0 +1,5 @@
+package emit
+
+// Internals exported for tests only.
+
+var WallCorrection = wallCorrection
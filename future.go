@@ -0,0 +1,81 @@
+package emit
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/pshch-pshch/chia"
+)
+
+// ErrCanceled is returned by Future.Err for canceled Future.
+var ErrCanceled = errors.New("emit: canceled")
+
+// Future is a handle of a scheduled one-shot emission, that can be awaited or abandoned individually.
+type Future struct {
+	done  *chia.Signal
+	timer *time.Timer
+
+	mu   sync.Mutex
+	time time.Time
+	err  error
+}
+
+// At schedules one-shot emission at the given time. Past time fires immediately.
+func At(t time.Time) *Future {
+	return After(time.Until(t))
+}
+
+// After schedules one-shot emission after the given duration.
+func After(d time.Duration) *Future {
+	f := &Future{done: chia.NewSignal()}
+	f.timer = time.AfterFunc(d, func() { f.complete(time.Now(), nil) })
+
+	return f
+}
+
+// Done returns a channel that is closed when Future fires or is canceled.
+func (f *Future) Done() <-chan struct{} {
+	return f.done.C
+}
+
+// Err returns ErrCanceled if Future is canceled and nil otherwise.
+func (f *Future) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.err
+}
+
+// Time returns the time Future fired at or zero time if it didn't fire.
+func (f *Future) Time() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.time
+}
+
+// Cancel abandons Future and reports if it was pending, i.e. false is returned if Future already fired
+// or was canceled before.
+func (f *Future) Cancel() bool {
+	f.timer.Stop()
+
+	return f.complete(time.Time{}, ErrCanceled)
+}
+
+// complete resolves Future once and reports if it was pending
+func (f *Future) complete(t time.Time, err error) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	select {
+	case <-f.done.C:
+		return false
+	default:
+	}
+
+	f.time, f.err = t, err
+	f.done.Close()
+
+	return true
+}
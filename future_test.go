@@ -0,0 +1,45 @@
+package emit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pshch-pshch/emit"
+)
+
+func TestFuture_Done(t *testing.T) {
+	period := 1 * time.Millisecond
+	at := time.Now().Add(period)
+	future := emit.At(at)
+
+	<-future.Done()
+
+	if err := future.Err(); err != nil {
+		t.Fatalf("Fired future error is %v", err)
+	}
+	if fired := future.Time(); fired.Before(at) {
+		t.Fatalf("Future fired at %s, expected not before %s", fired, at)
+	}
+	if future.Cancel() {
+		t.Fatal("Fired future was canceled")
+	}
+}
+
+func TestFuture_Cancel(t *testing.T) {
+	period := 1 * time.Millisecond
+	future := emit.After(period)
+
+	if !future.Cancel() {
+		t.Fatal("Pending future wasn't canceled")
+	}
+
+	<-future.Done()
+	if err := future.Err(); err != emit.ErrCanceled {
+		t.Fatalf("Canceled future error is %v, expected %v", err, emit.ErrCanceled)
+	}
+
+	time.Sleep(2 * period)
+	if fired := future.Time(); !fired.IsZero() {
+		t.Fatalf("Canceled future fired at %s", fired)
+	}
+}
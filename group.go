@@ -0,0 +1,74 @@
+package emit
+
+import "time"
+
+// Group is a set of tick channels with phases evenly distributed over the tick interval
+// (e.g. for shard-per-ticker architectures). All channels are driven by a single Ticker
+// ticking n times per interval, which delivers its ticks to the channels in turn.
+type Group struct {
+	// C are the channels of the Group members: i-th channel ticks i*d/n later than the first one.
+	C []<-chan time.Time
+
+	t *Ticker
+	n time.Duration
+}
+
+// NewTickers creates a Group of n channels with default TickerConfig and provided tick interval.
+// See TickerConfig.NewTickers for details.
+func NewTickers(n int, d time.Duration) *Group {
+	return TickerConfig{}.NewTickers(n, d)
+}
+
+// NewTickers creates a Group of n channels driven by a single Ticker customized by TickerConfig.
+// The channels drop ticks for slow receivers and follow TickerConfig on Reset and Stop the same way as Ticker.C.
+// The driving Ticker period is d/n truncated to whole nanoseconds, so d must be at least n nanoseconds.
+// Budget is shared by the whole Group, Deferred Group waits for Start or StartAt.
+// RequireAck and History concern the driving Ticker channel only, so they have no effect on the Group.
+func (cfg TickerConfig) NewTickers(n int, d time.Duration) *Group {
+	if n <= 0 {
+		panic("emit: non-positive number of tickers for NewTickers")
+	}
+
+	g := &Group{
+		C: make([]<-chan time.Time, n),
+
+		t: cfg.NewTicker(groupPeriod(d, time.Duration(n))),
+		n: time.Duration(n),
+	}
+
+	// The first tick of the driving Ticker has sequence number 1
+	for i := range g.C {
+		g.C[i] = g.t.derive(uint64(n), uint64(i+1)%uint64(n))
+	}
+
+	return g
+}
+
+// Reset changes the tick interval of the Group keeping its channels. See Ticker.Reset for details.
+func (g *Group) Reset(d time.Duration) {
+	g.t.Reset(groupPeriod(d, g.n))
+}
+
+// groupPeriod returns the driving Ticker period for the tick interval d of n channels
+func groupPeriod(d, n time.Duration) time.Duration {
+	if d > 0 && d < n {
+		panic("emit: tick interval is shorter than number of tickers")
+	}
+	return d / n
+}
+
+// Start starts ticking of dormant Group created with TickerConfig.Deferred. See Ticker.Start for details.
+func (g *Group) Start() {
+	g.t.Start()
+}
+
+// StartAt schedules start of dormant Group created with TickerConfig.Deferred at the given time.
+// See Ticker.StartAt for details.
+func (g *Group) StartAt(at time.Time) {
+	g.t.StartAt(at)
+}
+
+// Stop turns off the Group. See Ticker.Stop for details.
+func (g *Group) Stop() {
+	g.t.Stop()
+}
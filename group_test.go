@@ -0,0 +1,78 @@
+package emit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pshch-pshch/emit"
+)
+
+func TestGroup_Stagger(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping tickers phase check in short mode")
+	}
+
+	const n = 4
+	period := 200 * time.Millisecond
+	group := emit.NewTickers(n, period)
+	defer group.Stop()
+
+	ticks := make([]time.Time, n)
+	for i, c := range group.C {
+		ticks[i] = <-c
+	}
+
+	step := period / n
+	slop := step / 2
+	for i := 1; i < n; i++ {
+		if dt := ticks[i].Sub(ticks[i-1]); dt < step-slop || dt > step+slop {
+			t.Fatalf("Tickers %d and %d phase difference is %s, expected [%s,%s]", i-1, i, dt, step-slop, step+slop)
+		}
+	}
+
+	// The first member ticks again after the whole period
+	if dt := (<-group.C[0]).Sub(ticks[0]); dt < period-slop || dt > period+slop {
+		t.Fatalf("Ticker 0 period is %s, expected [%s,%s]", dt, period-slop, period+slop)
+	}
+}
+
+func TestGroup_Deferred(t *testing.T) {
+	period := 4 * time.Millisecond
+	group := emit.TickerConfig{
+		Deferred: true,
+	}.NewTickers(2, period)
+	defer group.Stop()
+
+	select {
+	case <-group.C[0]:
+		t.Fatal("Can receive from dormant group")
+	case <-time.After(2 * period):
+	}
+
+	group.Start()
+	<-group.C[0]
+	<-group.C[1]
+}
+
+func TestGroup_ShortInterval(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Group with interval shorter than number of tickers was created")
+		}
+	}()
+
+	emit.NewTickers(4, 3*time.Nanosecond)
+}
+
+func TestGroup_ResetShortInterval(t *testing.T) {
+	group := emit.NewTickers(4, time.Hour)
+	defer group.Stop()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Group was reset to interval shorter than number of tickers")
+		}
+	}()
+
+	group.Reset(3 * time.Nanosecond)
+}
@@ -0,0 +1,87 @@
+package emit
+
+import "time"
+
+// Healthy reports if the Ticker goroutine is not starved (see TickerConfig.StarvationThreshold).
+func (t *Ticker) Healthy() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return !t.starved
+}
+
+// checkHealth detects the Ticker goroutine starvation by the lateness of upstream tick received at now
+func (t *Ticker) checkHealth(tick, now time.Time) {
+	if t.cfg.StarvationThreshold <= 0 {
+		return
+	}
+
+	// Tick is received late if the Ticker goroutine is starved,
+	// and ticks are sent with gaps if the internal time.Ticker is starved itself
+	// Time spent in hooks delays ticks as well, but it is not starvation
+	lateness := now.Sub(tick) - t.busyWithin(tick, now)
+	if t.ticker != nil && !t.lastTick.IsZero() {
+		if gap := tick.Sub(t.lastTick) - t.period - t.busyWithin(t.lastTick, tick); gap > lateness {
+			lateness = gap
+		}
+	}
+	t.lastTick = tick
+
+	// Earlier hook calls don't overlap with the next tick gap
+	busy := t.busy[:0]
+	for _, b := range t.busy {
+		if b.to.After(tick) {
+			busy = append(busy, b)
+		}
+	}
+	t.busy = busy
+
+	starved := lateness > t.cfg.StarvationThreshold
+
+	t.mu.Lock()
+	changed := starved != t.starved
+	t.starved = starved
+	t.mu.Unlock()
+
+	if onHealth := t.cfg.OnHealth; changed && onHealth != nil {
+		t.notify(func() { onHealth(!starved, lateness) })
+	}
+}
+
+// tickerBusy is a time interval the Ticker goroutine spent in user hooks
+type tickerBusy struct {
+	from, to time.Time
+}
+
+// spend charges the tick to the budget recording the time spent in Budget.Cost for starvation detection
+func (t *Ticker) spend(tick time.Time) bool {
+	if t.cfg.StarvationThreshold <= 0 || t.budget.Cost == nil {
+		return t.budget.spend(tick)
+	}
+
+	from := time.Now()
+	defer func() {
+		t.busy = append(t.busy, tickerBusy{from: from, to: time.Now()})
+	}()
+
+	return t.budget.spend(tick)
+}
+
+// busyWithin returns the time spent in user hooks between from and to
+func (t *Ticker) busyWithin(from, to time.Time) time.Duration {
+	var d time.Duration
+	for _, b := range t.busy {
+		start, end := b.from, b.to
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			d += end.Sub(start)
+		}
+	}
+
+	return d
+}
@@ -0,0 +1,93 @@
+package emit
+
+import "time"
+
+// Emission is a record of a tick sent to Ticker channel.
+type Emission struct {
+	// Time is the tick value. Unlike delivered ticks, it is not converted to TickerConfig.Location.
+	Time time.Time
+	// Delivered reports if the tick was received by the consumer.
+	// Delivery is noticed on the next Ticker event (tick, Reset, Stop etc.) or History call.
+	Delivered bool
+	// Coalesced reports if the tick was dropped unconsumed (replaced by a later tick or drained on Reset or Stop).
+	Coalesced bool
+	// Latency is the time passed since the tick until the consumer acknowledged it with Ack.
+	// It is measured in TickerConfig.RequireAck mode only and is zero otherwise.
+	Latency time.Duration
+}
+
+// history is a ring buffer of recent emissions.
+// The last emission stays pending until it is delivered or coalesced.
+type history struct {
+	buf     []Emission
+	n       int
+	pending bool
+}
+
+func newHistory(size int) history {
+	if size < 0 {
+		panic("emit: negative history size")
+	}
+	return history{buf: make([]Emission, size)}
+}
+
+func (h *history) enabled() bool {
+	return len(h.buf) > 0
+}
+
+func (h *history) push(tick time.Time) {
+	h.buf[h.n%len(h.buf)] = Emission{Time: tick}
+	h.n++
+	h.pending = true
+}
+
+// deliver resolves pending emission as delivered to the consumer
+func (h *history) deliver() {
+	if !h.pending {
+		return
+	}
+
+	h.last().Delivered = true
+	h.pending = false
+}
+
+// ack resolves the last emission as delivered and acknowledged by the consumer at now
+func (h *history) ack(now time.Time) {
+	if h.n == 0 || h.last().Coalesced {
+		return
+	}
+
+	e := h.last()
+	e.Delivered = true
+	e.Latency = now.Sub(e.Time)
+	h.pending = false
+}
+
+// coalesce resolves pending emission as dropped unconsumed
+func (h *history) coalesce() {
+	if !h.pending {
+		return
+	}
+
+	h.last().Coalesced = true
+	h.pending = false
+}
+
+func (h *history) last() *Emission {
+	return &h.buf[(h.n-1)%len(h.buf)]
+}
+
+// list returns emissions from the oldest to the newest
+func (h *history) list() []Emission {
+	size := len(h.buf)
+	if h.n < size {
+		size = h.n
+	}
+
+	l := make([]Emission, 0, size)
+	for i := h.n - size; i < h.n; i++ {
+		l = append(l, h.buf[i%len(h.buf)])
+	}
+
+	return l
+}
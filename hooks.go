@@ -0,0 +1,60 @@
+package emit
+
+import "sync"
+
+// hooks is a queue of Ticker hook calls.
+// Hooks are called asynchronously in order of events on a separate goroutine,
+// so they may call Ticker methods without deadlocking the Ticker goroutine.
+type hooks struct {
+	mu    sync.Mutex
+	queue []func()
+	wake  chan struct{}
+}
+
+func newHooks() hooks {
+	return hooks{wake: make(chan struct{}, 1)}
+}
+
+// notify queues hook call
+func (t *Ticker) notify(f func()) {
+	t.hooks.mu.Lock()
+	t.hooks.queue = append(t.hooks.queue, f)
+	t.hooks.mu.Unlock()
+
+	select {
+	case t.hooks.wake <- struct{}{}:
+	default:
+	}
+}
+
+// runHooks calls queued hooks until the Ticker is stopped applying TickerConfig.OnPanic policy
+func (t *Ticker) runHooks() {
+	for {
+		select {
+		case <-t.hooks.wake:
+			t.callHooks()
+		case <-t.stop.Done:
+			t.callHooks()
+			return
+		}
+	}
+}
+
+func (t *Ticker) callHooks() {
+	for {
+		t.hooks.mu.Lock()
+		queue := t.hooks.queue
+		t.hooks.queue = nil
+		t.hooks.mu.Unlock()
+
+		if len(queue) == 0 {
+			return
+		}
+
+		for _, f := range queue {
+			if protect(t.cfg.OnPanic, t.cfg.PanicLog, f) && t.cfg.OnPanic == PanicStop {
+				t.Stop()
+			}
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package emit
+
+import (
+	"time"
+
+	"github.com/pshch-pshch/chia"
+)
+
+const (
+	// pacerStep is the maximum step of Rate integration
+	pacerStep = 10 * time.Millisecond
+	// pacerMaxRate limits Rate, so every operation moves the profile forward by at least a nanosecond
+	pacerMaxRate = float64(time.Second)
+)
+
+// Rate is a load profile: target rate in operations per second depending on the time elapsed since Pacer start.
+// Non-positive rate pauses emission.
+type Rate func(elapsed time.Duration) float64
+
+// ConstantRate returns Rate profile with the constant rate.
+func ConstantRate(perSecond float64) Rate {
+	return func(time.Duration) float64 {
+		return perSecond
+	}
+}
+
+// LinearRate returns Rate profile linearly ramping from one rate to another during the given duration
+// and keeping the final rate afterwards.
+func LinearRate(from, to float64, over time.Duration) Rate {
+	return func(elapsed time.Duration) float64 {
+		if elapsed >= over {
+			return to
+		}
+		return from + (to-from)*float64(elapsed)/float64(over)
+	}
+}
+
+// Pacer drives load generation with open-loop scheduling: it emits the intended start time of each operation
+// following the Rate profile even if the consumer lags, so late operations are delivered back-to-back
+// and their latency can be measured from the intended time rather than from the actual one.
+// The k-th operation is due when the Rate integral since Pacer start reaches k, so ramps from zero rate
+// start smoothly. Rate is limited by one operation per nanosecond.
+// Unlike Ticker, Pacer never drops ticks.
+type Pacer struct {
+	// The channel on which the intended start times are delivered. It is closed on Pacer stop.
+	C <-chan time.Time
+
+	c chan time.Time
+
+	rate Rate
+	cfg  PacerConfig
+	stop *chia.Shutdown
+}
+
+// NewPacer creates a new Pacer with default PacerConfig following the Rate profile from now.
+func NewPacer(rate Rate) *Pacer {
+	return PacerConfig{}.NewPacer(rate)
+}
+
+// PacerConfig allows Pacer startup customization.
+type PacerConfig struct {
+	// OnPanic determines how panics in Rate are handled.
+	// PanicRestart treats the Rate as zero for the integration step.
+	OnPanic PanicPolicy
+	// PanicLog is called with recovered panic value by PanicStop and PanicRestart policies.
+	// Nil PanicLog writes the value and the stack trace to the standard logger.
+	PanicLog func(v interface{})
+}
+
+// NewPacer creates Pacer customized by PacerConfig following the Rate profile from now.
+func (cfg PacerConfig) NewPacer(rate Rate) *Pacer {
+	c := make(chan time.Time)
+
+	p := &Pacer{
+		C: c, c: c,
+
+		rate: rate,
+		cfg:  cfg,
+		stop: chia.NewShutdown(),
+	}
+
+	go p.run()
+
+	return p
+}
+
+// Stop turns off Pacer and closes its channel.
+func (p *Pacer) Stop() {
+	p.stop.CloseAndWait()
+}
+
+func (p *Pacer) run() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
+	start := time.Now()
+
+	var elapsed time.Duration
+	var due float64 // Number of operations due by elapsed time, i.e. Rate integral
+	for k := 1.0; ; k++ {
+		for due < k {
+			// Midpoint rate integrates linear ramps exactly
+			rate, ok := p.evalRate(elapsed + pacerStep/2)
+			if !ok {
+				p.terminate()
+				return
+			}
+
+			// Compare in floats first: tiny rates overflow time.Duration
+			if need := (k - due) / rate * float64(time.Second); rate > 0 && need < float64(pacerStep) {
+				step := time.Duration(need)
+				if step < 1 {
+					step = 1
+				}
+				elapsed += step
+				due = k
+				break
+			}
+
+			elapsed += pacerStep
+			due += rate * pacerStep.Seconds()
+
+			// Don't run ahead of time, e.g. while Rate is zero
+			if !p.wait(timer, start.Add(elapsed)) {
+				return
+			}
+		}
+
+		// Intended time in the past means the consumer lags: emit immediately
+		at := start.Add(elapsed)
+		if !p.wait(timer, at) {
+			return
+		}
+
+		select {
+		case done := <-p.stop.Init:
+			p.handleStop(done)
+			return
+		case p.c <- at:
+		}
+	}
+}
+
+// evalRate returns the clamped Rate or false if Pacer must be stopped according to PacerConfig.OnPanic policy
+func (p *Pacer) evalRate(elapsed time.Duration) (rate float64, ok bool) {
+	if protect(p.cfg.OnPanic, p.cfg.PanicLog, func() { rate = p.rate(elapsed) }) {
+		return 0, p.cfg.OnPanic != PanicStop
+	}
+
+	switch {
+	case rate > pacerMaxRate:
+		return pacerMaxRate, true
+	case rate > 0:
+		return rate, true
+	default:
+		// Including NaN
+		return 0, true
+	}
+}
+
+// wait sleeps until the given time and reports false if Pacer is stopped meanwhile
+func (p *Pacer) wait(timer *time.Timer, until time.Time) bool {
+	d := time.Until(until)
+	if d <= 0 {
+		return true
+	}
+
+	timer.Reset(d)
+	select {
+	case done := <-p.stop.Init:
+		if !timer.Stop() {
+			<-timer.C
+		}
+		p.handleStop(done)
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (p *Pacer) handleStop(done func()) {
+	close(p.c)
+
+	done()
+}
+
+// terminate stops Pacer from its own goroutine
+func (p *Pacer) terminate() {
+	p.handleStop(p.stop.Terminate)
+}
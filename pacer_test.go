@@ -0,0 +1,151 @@
+package emit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pshch-pshch/emit"
+)
+
+func TestPacer_Rate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping pacer rate check in short mode")
+	}
+
+	const n = 1000
+	pacer := emit.NewPacer(emit.ConstantRate(n))
+	defer pacer.Stop()
+
+	t0 := time.Now()
+	for i := 0; i < n; i++ {
+		<-pacer.C
+	}
+	t1 := time.Now()
+	dt := t1.Sub(t0)
+
+	expected := time.Second
+	slop := expected * 2 / 10
+	if dt < expected-slop || dt > expected+slop {
+		t.Fatalf("%d paced operations took %s, expected [%s,%s]", n, dt, expected-slop, expected+slop)
+	}
+}
+
+func TestPacer_OpenLoop(t *testing.T) {
+	period := 1 * time.Millisecond
+	pacer := emit.NewPacer(emit.ConstantRate(float64(time.Second / period)))
+	defer pacer.Stop()
+
+	t0 := <-pacer.C
+	time.Sleep(10 * period)
+
+	// Lagging consumer still receives every intended time
+	for i := 1; i <= 10; i++ {
+		if t1 := <-pacer.C; t1.Sub(t0) != time.Duration(i)*period {
+			t.Fatalf("Operation %d intended at %s, expected %s", i, t1.Sub(t0), time.Duration(i)*period)
+		}
+	}
+}
+
+func TestPacer_LinearRate(t *testing.T) {
+	rate := emit.LinearRate(100, 200, time.Second)
+
+	for _, c := range []struct {
+		elapsed time.Duration
+		rate    float64
+	}{
+		{0, 100},
+		{time.Second / 2, 150},
+		{time.Second, 200},
+		{time.Minute, 200},
+	} {
+		if r := rate(c.elapsed); r != c.rate {
+			t.Fatalf("Rate after %s is %f, expected %f", c.elapsed, r, c.rate)
+		}
+	}
+}
+
+func TestPacer_Stop(t *testing.T) {
+	pacer := emit.NewPacer(emit.ConstantRate(1000))
+
+	<-pacer.C
+	pacer.Stop()
+
+	for range pacer.C {
+	}
+}
+
+func TestPacer_Ramp(t *testing.T) {
+	// Operations are due when the ramp integral 250*t² reaches their number
+	t0 := time.Now()
+	pacer := emit.NewPacer(emit.LinearRate(0, 1000, 2*time.Second))
+	defer pacer.Stop()
+
+	first := <-pacer.C
+	for i := 2; i < 11; i++ {
+		<-pacer.C
+	}
+	eleventh := <-pacer.C
+
+	if dt := first.Sub(t0); dt < 62*time.Millisecond || dt > 70*time.Millisecond {
+		t.Fatalf("The first operation intended at %s, expected about 63ms", dt)
+	}
+	if dt := eleventh.Sub(first); dt < 145*time.Millisecond || dt > 148*time.Millisecond {
+		t.Fatalf("The eleventh operation intended %s after the first one, expected about 146.6ms", dt)
+	}
+}
+
+func TestPacer_MaxRate(t *testing.T) {
+	const n = 10000
+	pacer := emit.NewPacer(emit.ConstantRate(1e12))
+	defer pacer.Stop()
+
+	t0 := <-pacer.C
+	var t1 time.Time
+	for i := 1; i < n; i++ {
+		t1 = <-pacer.C
+	}
+
+	// Rate is limited by one operation per nanosecond
+	if dt := t1.Sub(t0); dt < (n-1)*time.Nanosecond {
+		t.Fatalf("%d operations intended within %s, expected at least %s", n, dt, (n-1)*time.Nanosecond)
+	}
+}
+
+func TestPacer_TinyRate(t *testing.T) {
+	pacer := emit.NewPacer(emit.ConstantRate(1e-300))
+
+	select {
+	case <-pacer.C:
+		t.Fatal("Can receive from pacer with tiny rate")
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	pacer.Stop()
+}
+
+func TestPacer_PanicStop(t *testing.T) {
+	pacer := emit.PacerConfig{
+		OnPanic:  emit.PanicStop,
+		PanicLog: func(interface{}) {},
+	}.NewPacer(func(time.Duration) float64 { panic("rate") })
+
+	if _, ok := <-pacer.C; ok {
+		t.Fatal("Pacer channel is not closed after panic")
+	}
+	pacer.Stop() // Must not block on stopped pacer
+}
+
+func TestPacer_PanicRestart(t *testing.T) {
+	pacer := emit.PacerConfig{
+		OnPanic:  emit.PanicRestart,
+		PanicLog: func(interface{}) {},
+	}.NewPacer(func(elapsed time.Duration) float64 {
+		if elapsed < 20*time.Millisecond {
+			panic("rate")
+		}
+		return 1000
+	})
+	defer pacer.Stop()
+
+	<-pacer.C
+}
@@ -0,0 +1,71 @@
+package emit
+
+import (
+	"log"
+	"runtime/debug"
+)
+
+// PanicPolicy determines how panics on the package goroutines (e.g. in user provided hooks) are handled.
+type PanicPolicy int
+
+const (
+	// PanicRepanic doesn't recover panics, so they crash the program as usual.
+	PanicRepanic PanicPolicy = iota
+	// PanicStop recovers panic, logs it and stops the emitter (Ticker, Relay etc.) as if Stop was called.
+	PanicStop
+	// PanicRestart recovers panic, logs it and restarts the emitter goroutine keeping the emitter state.
+	PanicRestart
+)
+
+// supervise runs the Ticker goroutine applying TickerConfig.OnPanic policy
+func (t *Ticker) supervise() {
+	for protect(t.cfg.OnPanic, t.cfg.PanicLog, t.run) {
+		// Panic could happen while handling Stop
+		select {
+		case <-t.stop.Done:
+			return
+		default:
+		}
+
+		if t.cfg.OnPanic == PanicStop {
+			t.terminate()
+			return
+		}
+	}
+}
+
+// terminate stops the Ticker from its own goroutine
+func (t *Ticker) terminate() {
+	select {
+	case <-t.stop.Done:
+	default:
+		t.handleStop(t.stop.Terminate)
+	}
+}
+
+// protect runs f recovering its panic unless the policy is PanicRepanic and reports if f panicked.
+// Recovered panic value is passed to logf or written to the standard logger if logf is nil.
+func protect(policy PanicPolicy, logf func(v interface{}), f func()) (panicked bool) {
+	if policy == PanicRepanic {
+		f()
+		return false
+	}
+
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+
+		panicked = true
+		if logf != nil {
+			logf(v)
+			return
+		}
+		log.Printf("emit: recovered panic: %v\n%s", v, debug.Stack())
+	}()
+
+	f()
+
+	return false
+}
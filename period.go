@@ -0,0 +1,79 @@
+package emit
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrPeriodDelivered is returned by PeriodTracker.Track for the tick of already delivered period.
+	ErrPeriodDelivered = errors.New("emit: period already delivered")
+	// ErrPeriodOutOfRange is returned by PeriodTracker.Track for the tick preceding the first period.
+	ErrPeriodOutOfRange = errors.New("emit: tick precedes the first period")
+)
+
+// PeriodTracker assigns canonical period indexes to delivered ticks, so that per-period accounting
+// (e.g. billing) stays correct even when Ticker drops ticks for slow receivers.
+// Index k corresponds to the tick expected at start + k*period, so the first tick has index 1.
+// Every index is accepted at most once and skipped indexes are reported as gaps.
+// PeriodTracker is not safe for concurrent use.
+type PeriodTracker struct {
+	start  time.Time
+	period time.Duration
+
+	next int64
+	gaps int64
+}
+
+// NewPeriodTracker creates PeriodTracker for ticks of the given period started at the given time
+// (e.g. right before NewTicker call).
+func NewPeriodTracker(start time.Time, period time.Duration) *PeriodTracker {
+	if period <= 0 {
+		panic("emit: non-positive period for NewPeriodTracker")
+	}
+
+	return &PeriodTracker{
+		start:  start,
+		period: period,
+
+		next: 1,
+	}
+}
+
+// Index returns the period index of the tick.
+// It is rounded to the nearest period boundary to tolerate ticks jitter.
+func (p *PeriodTracker) Index(tick time.Time) int64 {
+	d := tick.Sub(p.start) + p.period/2
+
+	// Division truncates toward zero, floor it for ticks preceding the start
+	index := int64(d / p.period)
+	if d < 0 && d%p.period != 0 {
+		index--
+	}
+
+	return index
+}
+
+// Track assigns the tick its period index and returns the number of periods skipped since the previous
+// accepted tick. Ticks that must be ignored are reported with ErrPeriodDelivered for already delivered index
+// and ErrPeriodOutOfRange for index preceding the first period (e.g. Ticker.Step right after creation).
+func (p *PeriodTracker) Track(tick time.Time) (index, gap int64, err error) {
+	index = p.Index(tick)
+	switch {
+	case index < 1:
+		return index, 0, ErrPeriodOutOfRange
+	case index < p.next:
+		return index, 0, ErrPeriodDelivered
+	}
+
+	gap = index - p.next
+	p.gaps += gap
+	p.next = index + 1
+
+	return index, gap, nil
+}
+
+// Gaps returns the total number of skipped periods.
+func (p *PeriodTracker) Gaps() int64 {
+	return p.gaps
+}
@@ -0,0 +1,55 @@
+package emit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pshch-pshch/emit"
+)
+
+func TestPeriodTracker_Track(t *testing.T) {
+	period := 1 * time.Minute
+	t0 := time.Now()
+	tracker := emit.NewPeriodTracker(t0, period)
+
+	for _, c := range []struct {
+		tick  time.Time
+		index int64
+		gap   int64
+		err   error
+	}{
+		{t0.Add(time.Second), 0, 0, emit.ErrPeriodOutOfRange},
+		{t0.Add(-period), -1, 0, emit.ErrPeriodOutOfRange},
+		{t0.Add(period + time.Second), 1, 0, nil},
+		{t0.Add(2*period - time.Second), 2, 0, nil},
+		{t0.Add(2 * period), 2, 0, emit.ErrPeriodDelivered},
+		{t0.Add(5 * period), 5, 2, nil},
+		{t0.Add(4 * period), 4, 0, emit.ErrPeriodDelivered},
+		{t0.Add(6 * period), 6, 0, nil},
+	} {
+		index, gap, err := tracker.Track(c.tick)
+		if index != c.index || gap != c.gap || err != c.err {
+			t.Fatalf("Tick at %s tracked as (%d, %d, %v), expected (%d, %d, %v)",
+				c.tick.Sub(t0), index, gap, err, c.index, c.gap, c.err)
+		}
+	}
+
+	if gaps := tracker.Gaps(); gaps != 2 {
+		t.Fatalf("Tracker reports %d gaps, expected 2", gaps)
+	}
+}
+
+func TestPeriodTracker_Ticker(t *testing.T) {
+	period := 1 * time.Millisecond
+	tracker := emit.NewPeriodTracker(time.Now(), period)
+	ticker := emit.NewTicker(period)
+	defer ticker.Stop()
+
+	<-ticker.C
+	time.Sleep(5 * period)
+
+	index, gap, err := tracker.Track(<-ticker.C)
+	if err != nil || gap == 0 || index < 2 {
+		t.Fatalf("Coalesced tick tracked as (%d, %d, %v), expected gap", index, gap, err)
+	}
+}
@@ -0,0 +1,142 @@
+package emit
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/pshch-pshch/chia"
+)
+
+// Fire is a schedule fire published by Relay.
+type Fire struct {
+	// Job is the name of the schedule.
+	Job string
+	// Time is the scheduled time of the fire, i.e. the tick value.
+	Time time.Time
+	// Seq is the sequence number of the fire starting from 1.
+	Seq uint64
+}
+
+// Publisher delivers fires to distributed consumers via transport implemented by the caller (Kafka, NATS etc.).
+type Publisher interface {
+	// Publish delivers the fire. Context is canceled on Relay stop.
+	Publish(ctx context.Context, f Fire) error
+}
+
+// PublisherFunc is an adapter to use ordinary function as Publisher.
+type PublisherFunc func(ctx context.Context, f Fire) error
+
+// Publish calls f(ctx, fire).
+func (f PublisherFunc) Publish(ctx context.Context, fire Fire) error {
+	return f(ctx, fire)
+}
+
+// Relay publishes ticks received from a channel (e.g. Ticker.C) to Publisher,
+// so the package can act as the clock source for distributed consumers.
+// Ticks are published one by one: ticks received during slow publishing are coalesced by the Ticker.
+type Relay struct {
+	job string
+	c   <-chan time.Time
+	p   Publisher
+	cfg RelayConfig
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	stop   *chia.Shutdown
+
+	seq uint64
+}
+
+// NewRelay creates a new Relay with default RelayConfig publishing ticks from c as fires of the job.
+func NewRelay(job string, c <-chan time.Time, p Publisher) *Relay {
+	return RelayConfig{}.NewRelay(job, c, p)
+}
+
+// RelayConfig allows Relay startup customization.
+type RelayConfig struct {
+	// OnError is called with the fire that failed to publish. Nil OnError writes errors to the standard logger.
+	// Failures caused by Relay stop are not reported.
+	OnError func(f Fire, err error)
+	// OnPanic determines how panics in Publisher and OnError are handled.
+	// PanicRestart skips the fire and goes on with the next one.
+	OnPanic PanicPolicy
+	// PanicLog is called with recovered panic value by PanicStop and PanicRestart policies.
+	// Nil PanicLog writes the value and the stack trace to the standard logger.
+	PanicLog func(v interface{})
+}
+
+// NewRelay creates Relay customized by RelayConfig. See RelayConfig description for details.
+func (cfg RelayConfig) NewRelay(job string, c <-chan time.Time, p Publisher) *Relay {
+	r := &Relay{
+		job: job, c: c, p: p,
+
+		cfg: cfg,
+	}
+
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	r.stop = chia.NewShutdown()
+
+	go r.run()
+
+	return r
+}
+
+// Stop turns off Relay canceling in-flight publishing and waits for its completion.
+// Relay stops by itself once the ticks channel is closed.
+func (r *Relay) Stop() {
+	r.cancel()
+	r.stop.CloseAndWait()
+}
+
+func (r *Relay) run() {
+	for {
+		// Fast path for stop
+		select {
+		case done := <-r.stop.Init:
+			done()
+			return
+		default:
+		}
+
+		select {
+		case done := <-r.stop.Init:
+			done()
+			return
+		case tick, ok := <-r.c:
+			if !ok {
+				r.terminate()
+				return
+			}
+			// Stop cancels the context before it is received
+			if r.ctx.Err() != nil {
+				continue
+			}
+
+			if protect(r.cfg.OnPanic, r.cfg.PanicLog, func() { r.publish(tick) }) && r.cfg.OnPanic == PanicStop {
+				r.terminate()
+				return
+			}
+		}
+	}
+}
+
+// terminate stops Relay from its own goroutine
+func (r *Relay) terminate() {
+	r.cancel()
+	r.stop.Terminate()
+}
+
+func (r *Relay) publish(tick time.Time) {
+	r.seq++
+	f := Fire{Job: r.job, Time: tick, Seq: r.seq}
+
+	// Errors of publishing canceled by Stop are not reported
+	if err := r.p.Publish(r.ctx, f); err != nil && r.ctx.Err() == nil {
+		if r.cfg.OnError != nil {
+			r.cfg.OnError(f, err)
+			return
+		}
+		log.Printf("emit: failed to publish %s fire #%d: %v", f.Job, f.Seq, err)
+	}
+}
@@ -0,0 +1,123 @@
+package emit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pshch-pshch/emit"
+)
+
+func TestRelay_Publish(t *testing.T) {
+	period := 1 * time.Millisecond
+	ticker := emit.TickerConfig{
+		CloseOnStop: true,
+	}.NewTicker(period)
+
+	fires := make(chan emit.Fire, 3)
+	relay := emit.NewRelay("job", ticker.C, emit.PublisherFunc(func(ctx context.Context, f emit.Fire) error {
+		select {
+		case fires <- f:
+		default:
+		}
+		return nil
+	}))
+
+	for seq := uint64(1); seq <= 3; seq++ {
+		if f := <-fires; f.Job != "job" || f.Seq != seq || f.Time.IsZero() {
+			t.Fatalf("Published fire %+v, expected job fire #%d", f, seq)
+		}
+	}
+
+	ticker.Stop()
+	relay.Stop()
+}
+
+func TestRelay_Stop(t *testing.T) {
+	period := 1 * time.Millisecond
+	ticker := emit.NewTicker(period)
+	defer ticker.Stop()
+
+	published := make(chan struct{})
+	relay := emit.NewRelay("job", ticker.C, emit.PublisherFunc(func(ctx context.Context, f emit.Fire) error {
+		close(published)
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	<-published
+	relay.Stop() // Must cancel blocked publishing
+}
+
+func TestRelay_StopPending(t *testing.T) {
+	c := make(chan time.Time, 100)
+	for i := 0; i < cap(c); i++ {
+		c <- time.Now()
+	}
+
+	calls := make(chan struct{}, cap(c))
+	relay := emit.NewRelay("job", c, emit.PublisherFunc(func(ctx context.Context, f emit.Fire) error {
+		calls <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	<-calls
+	relay.Stop() // Pending ticks must not be published
+
+	if n := len(calls); n != 0 {
+		t.Fatalf("Publisher was called %d times after Stop", n)
+	}
+}
+
+func TestRelay_PanicStop(t *testing.T) {
+	period := 1 * time.Millisecond
+	ticker := emit.NewTicker(period)
+	defer ticker.Stop()
+
+	calls := make(chan struct{}, 10)
+	recovered := make(chan interface{}, 1)
+	relay := emit.RelayConfig{
+		OnPanic:  emit.PanicStop,
+		PanicLog: func(v interface{}) { recovered <- v },
+	}.NewRelay("job", ticker.C, emit.PublisherFunc(func(context.Context, emit.Fire) error {
+		calls <- struct{}{}
+		panic("publish")
+	}))
+
+	if v := <-recovered; v != "publish" {
+		t.Fatalf("Recovered %v, expected publish", v)
+	}
+	relay.Stop() // Must not block on stopped relay
+
+	time.Sleep(3 * period)
+	if n := len(calls); n != 1 {
+		t.Fatalf("Publisher was called %d times after panic, expected 1", n)
+	}
+}
+
+func TestRelay_PanicRestart(t *testing.T) {
+	period := 1 * time.Millisecond
+	ticker := emit.NewTicker(period)
+	defer ticker.Stop()
+
+	fires := make(chan emit.Fire, 1)
+	relay := emit.RelayConfig{
+		OnPanic:  emit.PanicRestart,
+		PanicLog: func(interface{}) {},
+	}.NewRelay("job", ticker.C, emit.PublisherFunc(func(ctx context.Context, f emit.Fire) error {
+		if f.Seq == 1 {
+			panic("publish")
+		}
+		select {
+		case fires <- f:
+		default:
+		}
+		return nil
+	}))
+	defer relay.Stop()
+
+	if f := <-fires; f.Seq != 2 {
+		t.Fatalf("Published fire #%d after panic, expected #2", f.Seq)
+	}
+}
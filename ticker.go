@@ -20,7 +20,41 @@ type Ticker struct {
 
 	stop   *chia.Shutdown
 	reset  chan tickerReset
+	call   chan tickerCall
 	ticker *time.Ticker
+	wall   *wallTicker
+
+	period  time.Duration
+	dormant bool
+	frozen  bool
+	startAt *time.Timer
+
+	budget budget
+	hooks  hooks
+
+	ack      chan struct{}
+	awaiting bool
+	ackTimer *time.Timer
+	held     time.Time
+	holding  bool
+
+	seq     uint64
+	derived []tickerDerived
+
+	lastTick time.Time
+	busy     []tickerBusy
+
+	mu      sync.Mutex
+	stats   TickerStats
+	history history
+	starved bool
+}
+
+// TickerStats contains Ticker runtime statistics.
+type TickerStats struct {
+	// DriftCorrection is a total difference between wall and monotonic clock elapsed time
+	// applied to long period ticks scheduling (see TickerConfig.DriftCheck).
+	DriftCorrection time.Duration
 }
 
 type tickerReset struct {
@@ -28,12 +62,28 @@ type tickerReset struct {
 	done func()
 }
 
+type tickerCall struct {
+	f    func()
+	done func()
+}
+
+// tickerDerived is a channel receiving upstream ticks with sequence number equal to r modulo n
+type tickerDerived struct {
+	n, r uint64
+	c    chan time.Time
+}
+
 // NewTicker creates a new Ticker with default TickerConfig and provided tick interval.
 // Unlike in time.NewTicker duration can be zero, which leads to paused ticker that can be reset later.
 func NewTicker(d time.Duration) *Ticker {
 	return TickerConfig{}.NewTicker(d)
 }
 
+// NewPausedTicker creates a new paused Ticker with default TickerConfig. Use Reset to start ticking.
+func NewPausedTicker() *Ticker {
+	return TickerConfig{}.NewPausedTicker()
+}
+
 // TickerConfig allows Ticker startup customization.
 type TickerConfig struct {
 	// CloseOnStop determines if ticks channel will be closed on Ticker stop (e.g. to range over it).
@@ -42,6 +92,46 @@ type TickerConfig struct {
 	DropTickOnReset bool
 	// DropTickOnStop determines if unconsumed tick will be dropped on Stop.
 	DropTickOnStop bool
+	// Budget limits emission by the cost of ticks. Zero Budget disables limiting.
+	Budget Budget
+	// DriftCheck enables wall clock scheduling for periods longer than DriftCheck.
+	// Such ticks are not relying on a single long timer: sleep target is recomputed against the wall clock
+	// at least every DriftCheck, so process clock skew doesn't make e.g. daily ticks fire minutes late.
+	// Zero DriftCheck disables wall clock scheduling.
+	DriftCheck time.Duration
+	// WallClock returns the wall clock time for DriftCheck scheduling. Nil WallClock means time.Now.
+	// Its monotonic clock reading is ignored.
+	WallClock func() time.Time
+	// Deferred determines if Ticker will be created dormant and start ticking only after Start or StartAt call.
+	Deferred bool
+	// History is a number of recent emissions kept for History. Zero History disables recording.
+	History int
+	// RequireAck determines if Ticker will wait for Ack of the delivered tick before delivering the next one.
+	// Ticks arriving meanwhile are coalesced: only the latest one will be sent after Ack.
+	RequireAck bool
+	// AckTimeout limits waiting for Ack of the tick received from C. Zero AckTimeout means waiting forever.
+	// Unreceived tick is never replaced, so there is at most one tick in flight even on timeout.
+	AckTimeout time.Duration
+	// StarvationThreshold enables detection of the Ticker goroutine starvation (e.g. by overloaded runtime):
+	// Ticker is unhealthy while upstream ticks arrive later than expected by more than StarvationThreshold.
+	// Unlike slow consumers, starvation delays all ticks. Time spent in Budget.Cost is not counted as lateness,
+	// so slow Cost doesn't make Ticker unhealthy. Zero StarvationThreshold disables detection.
+	StarvationThreshold time.Duration
+	// OnHealth is called when Ticker becomes unhealthy or recovers with the lateness of the upstream tick
+	// that changed the health. It is called asynchronously, so it may call any Ticker method
+	// (e.g. Reset to a longer period).
+	OnHealth func(healthy bool, lateness time.Duration)
+	// Location converts ticks to the given location (e.g. time.UTC) before delivering.
+	// Nil Location keeps ticks in time.Local.
+	// Note that conversion strips the monotonic clock reading (see time package documentation),
+	// so delivered ticks are compared with time.Since etc. by the wall clock that may jump.
+	// Ticker keeps original ticks internally, e.g. for Budget.Cost and History.
+	Location *time.Location
+	// OnPanic determines how panics on the Ticker goroutine (e.g. in Budget hooks) are handled.
+	OnPanic PanicPolicy
+	// PanicLog is called with recovered panic value by PanicStop and PanicRestart policies.
+	// Nil PanicLog writes the value and the stack trace to the standard logger.
+	PanicLog func(v interface{})
 }
 
 // NewTicker creates Ticker customized by TickerConfig. See TickerConfig description for details.
@@ -56,18 +146,84 @@ func (cfg TickerConfig) NewTicker(d time.Duration) *Ticker {
 
 	t.stop = chia.NewShutdown()
 	t.reset = make(chan tickerReset)
+	t.call = make(chan tickerCall)
+	t.ack = make(chan struct{}, 1)
+	t.budget = newBudget(cfg.Budget)
+	t.hooks = newHooks()
+	t.history = newHistory(cfg.History)
 
-	t.newTicker(d)
+	t.period = d
+	t.dormant = cfg.Deferred
+	if !t.dormant {
+		t.newTicker(d)
+	}
 
-	go t.run()
+	go t.supervise()
+	if cfg.Budget.OnResume != nil || cfg.OnHealth != nil {
+		go t.runHooks()
+	}
 
 	return t
 }
 
+// NewPausedTicker creates paused Ticker customized by TickerConfig. Use Reset to start ticking.
+// It is the same as NewTicker(0), but makes the intent explicit.
+func (cfg TickerConfig) NewPausedTicker() *Ticker {
+	return cfg.NewTicker(0)
+}
+
+// TickerState is a Ticker lifecycle state.
+type TickerState int
+
+const (
+	// TickerRunning is a state of Ticker delivering ticks.
+	TickerRunning TickerState = iota
+	// TickerPaused is a state of Ticker reset to zero period.
+	TickerPaused
+	// TickerDormant is a state of Ticker created with TickerConfig.Deferred and not started yet.
+	TickerDormant
+	// TickerStopped is a state of Ticker after Stop.
+	TickerStopped
+)
+
+func (s TickerState) String() string {
+	switch s {
+	case TickerRunning:
+		return "running"
+	case TickerPaused:
+		return "paused"
+	case TickerDormant:
+		return "dormant"
+	case TickerStopped:
+		return "stopped"
+	default:
+		return "unknown"
+	}
+}
+
+// State returns current Ticker state.
+// Frozen Ticker is still reported as running (see Freeze).
+func (t *Ticker) State() TickerState {
+	state := TickerStopped
+	t.do(func() {
+		switch {
+		case t.dormant:
+			state = TickerDormant
+		case t.period == 0:
+			state = TickerPaused
+		default:
+			state = TickerRunning
+		}
+	})
+
+	return state
+}
+
 // Reset behaves almost like stopping the Ticker and creating a new one with another period,
 // but it keeps the same Ticker with the same channel for ticks delivering.
 // Zero duration will cause Ticker to pause.
 // Already stopped Ticker will not be altered (Reset is no-op in that case).
+// Dormant Ticker just remembers the new period and keeps waiting for Start.
 func (t *Ticker) Reset(d time.Duration) {
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -79,6 +235,97 @@ func (t *Ticker) Reset(d time.Duration) {
 	}
 }
 
+// Start starts ticking of dormant Ticker created with TickerConfig.Deferred.
+// It cancels pending StartAt. Already started or stopped Ticker will not be altered.
+func (t *Ticker) Start() {
+	t.do(t.start)
+}
+
+// StartAt schedules start of dormant Ticker created with TickerConfig.Deferred at the given time,
+// so the first tick will be delivered one period later. Past time starts Ticker immediately.
+// It replaces previously scheduled start. Already started or stopped Ticker will not be altered.
+func (t *Ticker) StartAt(at time.Time) {
+	t.do(func() {
+		if !t.dormant {
+			return
+		}
+
+		t.stopStartAt()
+		t.startAt = time.NewTimer(time.Until(at))
+	})
+}
+
+// Ack acknowledges the tick received from C, allowing delivery of the next one (see TickerConfig.RequireAck).
+// Ack never blocks. It is ignored if there is no tick waiting for acknowledgement
+// or the awaited tick is not received from C yet, e.g. late Ack of the previous tick released by AckTimeout.
+func (t *Ticker) Ack() {
+	select {
+	case t.ack <- struct{}{}:
+	default:
+	}
+}
+
+// Freeze stops real-time emission for debugging: upstream ticks are dropped until Unfreeze,
+// while ticks still can be emitted one by one with Step.
+func (t *Ticker) Freeze() {
+	t.do(func() { t.frozen = true })
+}
+
+// Unfreeze resumes real-time emission stopped by Freeze.
+func (t *Ticker) Unfreeze() {
+	t.do(func() { t.frozen = false })
+}
+
+// Step emits exactly one tick with the current time, regardless of Ticker being frozen, paused or dormant.
+// The tick is handled as an upstream one, e.g. it is counted for Every and charged by Budget.
+// Stopped Ticker will not be altered.
+func (t *Ticker) Step() {
+	t.do(func() { t.handleTick(time.Now()) })
+}
+
+// Every returns a channel receiving every n-th tick of the Ticker, so that Every(10) and Every(100)
+// share a single timer and stay phase-locked: every 100th tick is also delivered to every 10th channel.
+// Ticks are counted since Ticker creation regardless of consumers, Reset keeps the count.
+// Derived channels drop ticks for slow receivers and follow TickerConfig on Reset and Stop the same way as C.
+func (t *Ticker) Every(n int) <-chan time.Time {
+	if n <= 0 {
+		panic("emit: non-positive divisor for Every")
+	}
+
+	return t.derive(uint64(n), 0)
+}
+
+// derive returns a channel receiving ticks with sequence number equal to r modulo n
+func (t *Ticker) derive(n, r uint64) <-chan time.Time {
+	c := make(chan time.Time, 1)
+	if !t.do(func() { t.derived = append(t.derived, tickerDerived{n, r, c}) }) && t.cfg.CloseOnStop {
+		close(c)
+	}
+
+	return c
+}
+
+// Stats returns Ticker runtime statistics.
+func (t *Ticker) Stats() TickerStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.stats
+}
+
+// History returns recent emissions from the oldest to the newest (see TickerConfig.History).
+func (t *Ticker) History() []Emission {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// The last emission is received if C is empty
+	if len(t.c) == 0 {
+		t.history.deliver()
+	}
+
+	return t.history.list()
+}
+
 // Stop turns off a ticker. After Stop, no more ticks will be sent.
 // Unlike time.Ticker.Stop, channel may be closed depending on TickerConfig.CloseOnStop.
 func (t *Ticker) Stop() {
@@ -95,70 +342,318 @@ func (t *Ticker) run() {
 		default:
 		}
 
-		switch t.ticker {
-		case nil:
-			// Just wait for stop or reset
-			select {
-			case done := <-t.stop.Init:
-				t.handleStop(done)
-				return
-			case r := <-t.reset:
-				t.handleReset(r.d, r.done)
+		// Channels of inactive sources are nil and block forever
+		select {
+		case done := <-t.stop.Init:
+			t.handleStop(done)
+			return
+		case r := <-t.reset:
+			t.handleReset(r.d, r.done)
+		case c := <-t.call:
+			t.handleCall(c.f, c.done)
+		case tick := <-t.upstream():
+			t.checkHealth(tick, time.Now())
+			if !t.frozen {
+				t.handleTick(tick)
 			}
-		default:
-			// Wait for the next upstream tick
-			select {
-			case done := <-t.stop.Init:
-				t.handleStop(done)
-				return
-			case r := <-t.reset:
-				t.handleReset(r.d, r.done)
-			case tick := <-t.ticker.C:
-				t.drain()
-				t.c <- tick
+		case now := <-t.budget.refill():
+			if onResume := t.budget.OnResume; t.budget.replenish() && onResume != nil {
+				t.notify(func() { onResume(now) })
 			}
+		case <-t.pendingStart():
+			t.start()
+		case <-t.ack:
+			t.handleAck()
+		case <-t.ackTimeout():
+			t.handleAckTimeout()
+		}
+	}
+}
+
+func (t *Ticker) handleTick(tick time.Time) {
+	t.seq++
+
+	if !t.spend(tick) {
+		return
+	}
+
+	t.deliver(tick)
+	for _, d := range t.derived {
+		if t.seq%d.n == d.r {
+			send(d.c, t.local(tick))
 		}
 	}
 }
 
+func (t *Ticker) handleCall(f func(), done func()) {
+	defer done()
+
+	f()
+}
+
 func (t *Ticker) handleStop(done func()) {
+	defer done()
+
+	t.observe()
 	if t.cfg.DropTickOnStop {
 		t.drain()
 	}
 	if t.cfg.CloseOnStop {
 		close(t.c)
+		for _, d := range t.derived {
+			close(d.c)
+		}
 	}
 	t.newTicker(0)
-
-	done()
+	t.stopStartAt()
+	t.stopAckTimer()
+	t.budget.stop()
 }
 
 func (t *Ticker) handleReset(d time.Duration, done func()) {
+	defer done()
+
+	t.observe()
 	if t.cfg.DropTickOnReset {
 		t.drain()
 	}
-	t.newTicker(d)
+	t.period = d
+	if !t.dormant {
+		t.newTicker(d)
+	}
+}
+
+func (t *Ticker) start() {
+	if !t.dormant {
+		return
+	}
 
-	done()
+	t.stopStartAt()
+	t.dormant = false
+	t.newTicker(t.period)
 }
 
-// drain drops unconsumed buffered tick if any
-func (t *Ticker) drain() {
+// pendingStart returns StartAt timer channel or nil if there is no scheduled start
+func (t *Ticker) pendingStart() <-chan time.Time {
+	if t.startAt == nil {
+		return nil
+	}
+	return t.startAt.C
+}
+
+func (t *Ticker) stopStartAt() {
+	if t.startAt != nil {
+		t.startAt.Stop()
+		t.startAt = nil
+	}
+}
+
+// do runs f on the Ticker goroutine and waits for its completion.
+// It reports false without running f if Ticker is already stopped.
+func (t *Ticker) do(f func()) bool {
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	select {
+	case <-t.stop.Done:
+		return false
+	case t.call <- tickerCall{f, wg.Done}:
+		wg.Wait()
+		return true
+	}
+}
+
+// deliver emits tick to C unless previous one is not acknowledged yet
+func (t *Ticker) deliver(tick time.Time) {
+	if t.cfg.RequireAck {
+		if t.awaiting {
+			t.held, t.holding = tick, true
+			return
+		}
+
+		// Drop stale Ack of the previous tick
+		select {
+		case <-t.ack:
+		default:
+		}
+
+		t.awaiting = true
+		if t.cfg.AckTimeout > 0 {
+			t.ackTimer = time.NewTimer(t.cfg.AckTimeout)
+		}
+	}
+
+	t.emit(tick)
+}
+
+// handleAck releases awaited tick if it is received by the consumer
+func (t *Ticker) handleAck() {
+	if len(t.c) > 0 {
+		return
+	}
+
+	if t.awaiting && t.history.enabled() {
+		t.mu.Lock()
+		t.history.ack(time.Now())
+		t.mu.Unlock()
+	}
+	t.release()
+}
+
+// handleAckTimeout releases awaited tick if it is received by the consumer or keeps waiting otherwise
+func (t *Ticker) handleAckTimeout() {
+	if len(t.c) > 0 {
+		t.ackTimer = time.NewTimer(t.cfg.AckTimeout)
+		return
+	}
+
+	t.release()
+}
+
+// release stops waiting for Ack and delivers held tick if any
+func (t *Ticker) release() {
+	if !t.awaiting {
+		return
+	}
+
+	t.awaiting = false
+	t.stopAckTimer()
+
+	if t.holding {
+		t.holding = false
+		t.deliver(t.held)
+	}
+}
+
+// ackTimeout returns Ack timer channel or nil if Ticker is not waiting for Ack with timeout
+func (t *Ticker) ackTimeout() <-chan time.Time {
+	if t.ackTimer == nil {
+		return nil
+	}
+	return t.ackTimer.C
+}
+
+func (t *Ticker) stopAckTimer() {
+	if t.ackTimer != nil {
+		t.ackTimer.Stop()
+		t.ackTimer = nil
+	}
+}
+
+// emit delivers tick to C replacing unconsumed one and records it to the history
+func (t *Ticker) emit(tick time.Time) {
+	t.drainC()
+	t.c <- t.local(tick)
+
+	if t.history.enabled() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+
+		// Consumer could receive pending tick after drainC check
+		t.history.deliver()
+		t.history.push(tick)
+	}
+}
+
+// observe records delivery of pending emission to the history if the consumer has received it
+func (t *Ticker) observe() {
+	if !t.history.enabled() || len(t.c) > 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.history.deliver()
+}
+
+// drainC drops unconsumed tick from C recording it to the history as coalesced.
+// Dropped tick can't be acknowledged, so waiting for Ack is cancelled as well.
+func (t *Ticker) drainC() {
+	t.observe()
+
+	// History must not see C empty before the dropped tick is coalesced
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	select {
 	case <-t.c:
+		t.history.coalesce()
+
+		t.awaiting = false
+		t.stopAckTimer()
+	default:
+	}
+}
+
+// drain drops unconsumed buffered ticks if any
+func (t *Ticker) drain() {
+	t.drainC()
+	t.holding = false
+	for _, d := range t.derived {
+		drain(d.c)
+	}
+}
+
+// local converts tick to TickerConfig.Location if any
+func (t *Ticker) local(tick time.Time) time.Time {
+	if t.cfg.Location == nil {
+		return tick
+	}
+	return tick.In(t.cfg.Location)
+}
+
+// send delivers tick replacing unconsumed one
+func send(c chan time.Time, tick time.Time) {
+	drain(c)
+	c <- tick
+}
+
+func drain(c chan time.Time) {
+	select {
+	case <-c:
+	default:
+	}
+}
+
+// upstream returns internal ticker channel or nil if Ticker is paused
+func (t *Ticker) upstream() <-chan time.Time {
+	switch {
+	case t.ticker != nil:
+		return t.ticker.C
+	case t.wall != nil:
+		return t.wall.C
 	default:
+		return nil
 	}
 }
 
-// newTicker (re)creates internal time.Ticker
+// newTicker (re)creates internal time.Ticker or wallTicker for long periods
 func (t *Ticker) newTicker(d time.Duration) {
 	if t.ticker != nil {
 		t.ticker.Stop()
+		t.ticker = nil
+	}
+	if t.wall != nil {
+		t.wall.Stop()
+		t.wall = nil
 	}
 
-	if d == 0 {
-		t.ticker = nil
-	} else {
+	t.lastTick = time.Time{}
+	t.busy = t.busy[:0]
+
+	switch {
+	case d == 0:
+	case t.cfg.DriftCheck > 0 && d > t.cfg.DriftCheck:
+		t.wall = newWallTicker(d, t.cfg.DriftCheck, t.cfg.WallClock, t.addDriftCorrection)
+	default:
 		t.ticker = time.NewTicker(d)
 	}
 }
+
+func (t *Ticker) addDriftCorrection(correction time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stats.DriftCorrection += correction
+}
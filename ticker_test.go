@@ -2,6 +2,7 @@ package emit_test
 
 import (
 	"runtime"
+	"sync"
 	"testing"
 	"time"
 
@@ -194,3 +195,611 @@ func TestTicker_KeepOnStop(t *testing.T) {
 		t.Fatal("Can't receive from stopped ticker")
 	}
 }
+
+func TestTicker_Budget(t *testing.T) {
+	period := 1 * time.Millisecond
+	resumed := make(chan time.Time, 1)
+	ticker := emit.TickerConfig{
+		Budget: emit.Budget{
+			Capacity: 3,
+			Interval: 50 * period,
+			OnResume: func(now time.Time) { resumed <- now },
+		},
+	}.NewTicker(period)
+	defer ticker.Stop()
+
+	for i := 0; i < 3; i++ {
+		<-ticker.C
+	}
+
+	select {
+	case <-ticker.C:
+		t.Fatal("Can receive from ticker with exhausted budget")
+	case <-time.After(10 * period):
+	}
+
+	select {
+	case <-resumed:
+	case <-time.After(100 * period):
+		t.Fatal("Budget wasn't replenished")
+	}
+	<-ticker.C
+}
+
+func TestTicker_BudgetCost(t *testing.T) {
+	period := 1 * time.Millisecond
+	ticker := emit.TickerConfig{
+		Budget: emit.Budget{
+			Capacity: 2,
+			Interval: time.Hour,
+			Cost:     func(time.Time) int64 { return 5 },
+		},
+	}.NewTicker(period)
+	defer ticker.Stop()
+
+	// Tick costing more than Capacity is charged the whole Capacity
+	<-ticker.C
+
+	select {
+	case <-ticker.C:
+		t.Fatal("Can receive from ticker with exhausted budget")
+	case <-time.After(3 * period):
+	}
+}
+
+func TestTicker_BudgetResumeReentrant(t *testing.T) {
+	period := 1 * time.Millisecond
+	var ticker *emit.Ticker
+	created := make(chan struct{})
+	resumed := make(chan emit.TickerState, 1)
+	ticker = emit.TickerConfig{
+		Budget: emit.Budget{
+			Capacity: 1,
+			Interval: 5 * period,
+			OnResume: func(time.Time) {
+				<-created
+				ticker.Reset(2 * period) // Must not deadlock
+				select {
+				case resumed <- ticker.State():
+				default:
+				}
+			},
+		},
+	}.NewTicker(period)
+	close(created)
+	defer ticker.Stop()
+
+	select {
+	case state := <-resumed:
+		if state != emit.TickerRunning {
+			t.Fatalf("Ticker is %s on resume, expected %s", state, emit.TickerRunning)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Budget resume hook deadlocked")
+	}
+}
+
+func TestTicker_BudgetNegativeCapacity(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Ticker with negative budget capacity was created")
+		}
+	}()
+
+	emit.TickerConfig{
+		Budget: emit.Budget{Capacity: -1, Interval: time.Second},
+	}.NewTicker(time.Millisecond)
+}
+
+func TestTicker_Every(t *testing.T) {
+	period := 1 * time.Millisecond
+	ticker := emit.NewTicker(period)
+	defer ticker.Stop()
+
+	fast := ticker.Every(2)
+	slow := ticker.Every(10)
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-slow:
+			t.Fatal("Can receive from slow channel before fast one")
+		case <-fast:
+		}
+	}
+
+	t0 := <-slow
+	if t1 := <-fast; t1.Before(t0) {
+		t.Fatal("Fast and slow channels are not phase-locked")
+	}
+}
+
+func TestTicker_EveryCloseOnStop(t *testing.T) {
+	period := 1 * time.Millisecond
+	ticker := emit.TickerConfig{
+		CloseOnStop: true,
+	}.NewTicker(period)
+
+	c := ticker.Every(2)
+	<-c
+	ticker.Stop()
+
+	for range c {
+	}
+	if _, ok := <-ticker.Every(2); ok {
+		t.Fatal("Derived channel of stopped ticker is not closed")
+	}
+}
+
+func TestTicker_DriftCheck(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping wall clock ticker period check in short mode")
+	}
+
+	const n = 20
+	period := 10 * time.Millisecond
+	ticker := emit.TickerConfig{
+		DriftCheck: period / 3,
+	}.NewTicker(period)
+	defer ticker.Stop()
+
+	t0 := time.Now()
+	for i := 0; i < n; i++ {
+		<-ticker.C
+	}
+	t1 := time.Now()
+	dt := t1.Sub(t0)
+
+	expected := period * n
+	slop := expected * 2 / 10
+	if dt < expected-slop || dt > expected+slop {
+		t.Fatalf("%d %s ticks took %s, expected [%s,%s]", n, period, dt, expected-slop, expected+slop)
+	}
+
+	// Clocks don't skew in tests
+	if correction := ticker.Stats().DriftCorrection; correction < -slop || correction > slop {
+		t.Fatalf("Drift correction is %s, expected [%s,%s]", correction, -slop, slop)
+	}
+}
+
+func TestTicker_Start(t *testing.T) {
+	period := 1 * time.Millisecond
+	ticker := emit.TickerConfig{
+		Deferred: true,
+	}.NewTicker(period)
+	defer ticker.Stop()
+
+	select {
+	case <-ticker.C:
+		t.Fatal("Can receive from dormant ticker")
+	case <-time.After(2 * period):
+	}
+
+	ticker.Start()
+	<-ticker.C
+}
+
+func TestTicker_StartAt(t *testing.T) {
+	period := 1 * time.Millisecond
+	ticker := emit.TickerConfig{
+		Deferred: true,
+	}.NewTicker(period)
+	defer ticker.Stop()
+
+	at := time.Now().Add(10 * period)
+	ticker.StartAt(at)
+
+	if tick := <-ticker.C; tick.Before(at.Add(period)) {
+		t.Fatalf("First tick at %s, expected not before %s", tick, at.Add(period))
+	}
+}
+
+func TestTicker_History(t *testing.T) {
+	period := 1 * time.Millisecond
+	ticker := emit.TickerConfig{
+		History: 100,
+	}.NewTicker(period)
+
+	<-ticker.C
+	time.Sleep(10 * period)
+	<-ticker.C
+	t1 := <-ticker.C
+	ticker.Stop()
+
+	var coalesced, delivered bool
+	for _, e := range ticker.History() {
+		if e.Coalesced {
+			coalesced = true
+		}
+		if e.Time.Equal(t1) {
+			delivered = e.Delivered
+		}
+	}
+	if !coalesced {
+		t.Fatal("History doesn't contain coalesced emissions")
+	}
+	if !delivered {
+		t.Fatal("History doesn't contain last received emission")
+	}
+}
+
+func TestTicker_HistoryDelivered(t *testing.T) {
+	ticker := emit.TickerConfig{
+		History: 1,
+	}.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	ticker.Step()
+	tick := <-ticker.C
+
+	// Delivery is noticed without waiting for the next tick
+	if e := ticker.History()[0]; !e.Time.Equal(tick) || !e.Delivered {
+		t.Fatalf("Received emission is recorded as %+v", e)
+	}
+}
+
+func TestTicker_HistoryUnconsumed(t *testing.T) {
+	period := 1 * time.Millisecond
+	ticker := emit.TickerConfig{
+		History: 1000,
+	}.NewTicker(period)
+
+	stop := time.After(50 * period)
+	for polling := true; polling; {
+		select {
+		case <-stop:
+			polling = false
+		default:
+			ticker.History()
+		}
+	}
+	ticker.Stop()
+
+	// Nothing is received, so no emission may be recorded as delivered
+	for i, e := range ticker.History() {
+		if e.Delivered {
+			t.Fatalf("Unconsumed emission %d of %d is recorded as delivered", i, len(ticker.History()))
+		}
+	}
+}
+
+func TestTicker_HistoryLatency(t *testing.T) {
+	period := 1 * time.Millisecond
+	ticker := emit.TickerConfig{
+		History:    1,
+		RequireAck: true,
+	}.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	ticker.Step()
+	<-ticker.C
+	time.Sleep(5 * period)
+	ticker.Ack()
+
+	deadline := time.Now().Add(time.Second)
+	for ticker.History()[0].Latency == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Acknowledged emission latency is not recorded")
+		}
+		runtime.Gosched()
+	}
+	if latency := ticker.History()[0].Latency; latency < 5*period {
+		t.Fatalf("Acknowledged emission latency is %s, expected at least %s", latency, 5*period)
+	}
+}
+
+func TestTicker_Ack(t *testing.T) {
+	period := 1 * time.Millisecond
+	ticker := emit.TickerConfig{
+		RequireAck: true,
+	}.NewTicker(period)
+	defer ticker.Stop()
+
+	<-ticker.C
+
+	select {
+	case <-ticker.C:
+		t.Fatal("Can receive unacknowledged ticker")
+	case <-time.After(3 * period):
+	}
+
+	ticker.Ack()
+	<-ticker.C
+}
+
+func TestTicker_AckTimeout(t *testing.T) {
+	period := 1 * time.Millisecond
+	ticker := emit.TickerConfig{
+		RequireAck: true,
+		AckTimeout: 10 * period,
+	}.NewTicker(period)
+	defer ticker.Stop()
+
+	t0 := <-ticker.C
+	<-ticker.C
+	if dt := time.Since(t0); dt < 10*period {
+		t.Fatalf("Unacknowledged tick was released in %s, expected at least %s", dt, 10*period)
+	}
+}
+
+func TestTicker_LateAck(t *testing.T) {
+	period := 1 * time.Millisecond
+	ticker := emit.TickerConfig{
+		RequireAck: true,
+		AckTimeout: 5 * period,
+	}.NewTicker(period)
+	defer ticker.Stop()
+
+	<-ticker.C
+	time.Sleep(20 * period) // Let timeout release the next tick
+
+	// Late Ack of the first tick must not release unreceived one
+	acked := time.Now()
+	ticker.Ack()
+	time.Sleep(5 * period)
+
+	if tick := <-ticker.C; tick.After(acked) {
+		t.Fatal("Late Ack released unreceived tick")
+	}
+}
+
+func TestTicker_Freeze(t *testing.T) {
+	period := 1 * time.Millisecond
+	ticker := emit.NewTicker(period)
+	defer ticker.Stop()
+
+	<-ticker.C
+	ticker.Freeze()
+	select {
+	case <-ticker.C: // Tick could be sent before Freeze
+	default:
+	}
+
+	select {
+	case <-ticker.C:
+		t.Fatal("Can receive from frozen ticker")
+	case <-time.After(3 * period):
+	}
+
+	ticker.Step()
+	select {
+	case <-ticker.C:
+	default:
+		t.Fatal("Can't receive stepped tick")
+	}
+
+	ticker.Unfreeze()
+	<-ticker.C
+}
+
+func TestTicker_State(t *testing.T) {
+	period := 1 * time.Millisecond
+	ticker := emit.NewPausedTicker()
+
+	for _, c := range []struct {
+		change func()
+		state  emit.TickerState
+	}{
+		{func() {}, emit.TickerPaused},
+		{func() { ticker.Reset(period) }, emit.TickerRunning},
+		{func() { ticker.Reset(0) }, emit.TickerPaused},
+		{ticker.Stop, emit.TickerStopped},
+	} {
+		c.change()
+		if state := ticker.State(); state != c.state {
+			t.Fatalf("Ticker is %s, expected %s", state, c.state)
+		}
+	}
+
+	deferred := emit.TickerConfig{
+		Deferred: true,
+	}.NewTicker(period)
+	defer deferred.Stop()
+
+	if state := deferred.State(); state != emit.TickerDormant {
+		t.Fatalf("Deferred ticker is %s, expected %s", state, emit.TickerDormant)
+	}
+}
+
+func TestTicker_PanicStop(t *testing.T) {
+	period := 1 * time.Millisecond
+	recovered := make(chan interface{}, 1)
+	ticker := emit.TickerConfig{
+		CloseOnStop: true,
+		Budget: emit.Budget{
+			Capacity: 1,
+			Interval: period,
+			Cost:     func(time.Time) int64 { panic("cost") },
+		},
+		OnPanic:  emit.PanicStop,
+		PanicLog: func(v interface{}) { recovered <- v },
+	}.NewTicker(period)
+
+	if _, ok := <-ticker.C; ok {
+		t.Fatal("Ticker channel is not closed after panic")
+	}
+	if v := <-recovered; v != "cost" {
+		t.Fatalf("Recovered %v, expected cost", v)
+	}
+	if state := ticker.State(); state != emit.TickerStopped {
+		t.Fatalf("Ticker is %s after panic, expected %s", state, emit.TickerStopped)
+	}
+	ticker.Stop()
+}
+
+func TestTicker_PanicRestart(t *testing.T) {
+	period := 1 * time.Millisecond
+	panicked := false
+	ticker := emit.TickerConfig{
+		Budget: emit.Budget{
+			Capacity: 100,
+			Interval: time.Hour,
+			Cost: func(time.Time) int64 {
+				if !panicked {
+					panicked = true
+					panic("cost")
+				}
+				return 1
+			},
+		},
+		OnPanic:  emit.PanicRestart,
+		PanicLog: func(interface{}) {},
+	}.NewTicker(period)
+	defer ticker.Stop()
+
+	<-ticker.C
+	if !panicked {
+		t.Fatal("Budget cost didn't panic")
+	}
+}
+
+func TestTicker_Location(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	period := 1 * time.Millisecond
+	ticker := emit.TickerConfig{
+		Location: loc,
+	}.NewTicker(period)
+	defer ticker.Stop()
+
+	if tick := <-ticker.C; tick.Location() != loc {
+		t.Fatalf("Tick is in %s, expected %s", tick.Location(), loc)
+	}
+}
+
+func TestTicker_LocationHistory(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	ticker := emit.TickerConfig{
+		History:    1,
+		RequireAck: true,
+		Location:   loc,
+	}.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	ticker.Step()
+	tick := <-ticker.C
+	ticker.Ack()
+
+	deadline := time.Now().Add(time.Second)
+	for ticker.History()[0].Latency == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Acknowledged emission latency is not recorded")
+		}
+		runtime.Gosched()
+	}
+
+	// History keeps original tick with monotonic clock reading
+	if e := ticker.History()[0]; !e.Time.Equal(tick) || e.Time.Location() == loc {
+		t.Fatalf("Emission of %s is recorded as %+v", tick, e)
+	}
+}
+
+// starveRuntime occupies the only processor with busy goroutines for d
+func starveRuntime(d time.Duration) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+
+	deadline := time.Now().Add(d)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestTicker_Starvation(t *testing.T) {
+	period := 1 * time.Millisecond
+	health := make(chan bool, 2)
+	ticker := emit.TickerConfig{
+		StarvationThreshold: 5 * period,
+		OnHealth:            func(healthy bool, _ time.Duration) { health <- healthy },
+	}.NewTicker(period)
+	defer ticker.Stop()
+
+	if !ticker.Healthy() {
+		t.Fatal("New ticker is unhealthy")
+	}
+
+	starveRuntime(100 * period)
+	if healthy := <-health; healthy {
+		t.Fatal("Starved ticker is healthy")
+	}
+	if healthy := <-health; !healthy {
+		t.Fatal("Ticker didn't recover from starvation")
+	}
+	if !ticker.Healthy() {
+		t.Fatal("Recovered ticker is unhealthy")
+	}
+}
+
+func TestTicker_StarvationSlowCost(t *testing.T) {
+	period := 1 * time.Millisecond
+	health := make(chan bool, 1)
+	slow := make(chan struct{}, 3)
+	ticker := emit.TickerConfig{
+		Budget: emit.Budget{
+			Capacity: 1000,
+			Interval: time.Hour,
+			Cost: func(time.Time) int64 {
+				select {
+				case slow <- struct{}{}:
+					time.Sleep(10 * period) // Block the Ticker goroutine in the hook
+				default:
+				}
+				return 1
+			},
+		},
+		StarvationThreshold: 5 * period,
+		OnHealth:            func(healthy bool, _ time.Duration) { health <- healthy },
+	}.NewTicker(period)
+	defer ticker.Stop()
+
+	for i := 0; i < 10; i++ {
+		<-ticker.C
+	}
+
+	select {
+	case <-health:
+		t.Fatal("Slow budget cost is reported as starvation")
+	default:
+	}
+	if !ticker.Healthy() {
+		t.Fatal("Ticker with slow budget cost is unhealthy")
+	}
+}
+
+func TestTicker_StarvationReentrant(t *testing.T) {
+	period := 1 * time.Millisecond
+	var ticker *emit.Ticker
+	created := make(chan struct{})
+	degraded := make(chan emit.TickerState, 1)
+	ticker = emit.TickerConfig{
+		StarvationThreshold: 5 * period,
+		OnHealth: func(healthy bool, _ time.Duration) {
+			if healthy {
+				return
+			}
+
+			<-created
+			ticker.Reset(10 * period) // Must not deadlock
+			select {
+			case degraded <- ticker.State():
+			default:
+			}
+		},
+	}.NewTicker(period)
+	close(created)
+	defer ticker.Stop()
+
+	starveRuntime(100 * period)
+	select {
+	case state := <-degraded:
+		if state != emit.TickerRunning {
+			t.Fatalf("Ticker is %s after degradation, expected %s", state, emit.TickerRunning)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Health hook deadlocked")
+	}
+}